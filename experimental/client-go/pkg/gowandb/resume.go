@@ -0,0 +1,64 @@
+package gowandb
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/wandb/wandb/experimental/client-go/pkg/opts/runopts"
+)
+
+// localRunDir is where the Go and Python SDKs write local run
+// directories, named "run-<start-time>-<run-id>".
+const localRunDir = "wandb"
+
+// resolveResume applies the semantics of the four resume modes
+// (runopts.ResumeAllow/Must/Never/Auto) and reports whether the run
+// being created should be marked as resumed. "must" and "never" check
+// existence against the server via client; "auto" checks for a local
+// .wandb file for runID instead and does not contact the server.
+func resolveResume(client ServiceClient, runID string, mode string) (resumed bool, err error) {
+	switch mode {
+	case runopts.ResumeAllow:
+		exists, err := client.RunExists(runID)
+		if err != nil {
+			return false, fmt.Errorf("gowandb: checking whether run %q exists: %w", runID, err)
+		}
+		return exists, nil
+
+	case runopts.ResumeMust:
+		exists, err := client.RunExists(runID)
+		if err != nil {
+			return false, fmt.Errorf("gowandb: checking whether run %q exists: %w", runID, err)
+		}
+		if !exists {
+			return false, fmt.Errorf("gowandb: resume mode %q requires run %q to already exist on the server", runopts.ResumeMust, runID)
+		}
+		return true, nil
+
+	case runopts.ResumeNever:
+		exists, err := client.RunExists(runID)
+		if err != nil {
+			return false, fmt.Errorf("gowandb: checking whether run %q exists: %w", runID, err)
+		}
+		if exists {
+			return false, fmt.Errorf("gowandb: resume mode %q requires run %q to not already exist on the server", runopts.ResumeNever, runID)
+		}
+		return false, nil
+
+	case runopts.ResumeAuto:
+		return localRunFileExists(runID)
+
+	default:
+		return false, fmt.Errorf("gowandb: invalid resume mode %q", mode)
+	}
+}
+
+// localRunFileExists reports whether a local .wandb file for runID is
+// present, which is what "auto" resume uses to decide whether to resume.
+func localRunFileExists(runID string) (bool, error) {
+	matches, err := filepath.Glob(filepath.Join(localRunDir, "run-*-"+runID, "run-"+runID+".wandb"))
+	if err != nil {
+		return false, fmt.Errorf("gowandb: checking local run file for %q: %w", runID, err)
+	}
+	return len(matches) > 0, nil
+}
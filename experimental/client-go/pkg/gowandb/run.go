@@ -0,0 +1,154 @@
+package gowandb
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/wandb/wandb/experimental/client-go/pkg/opts/runopts"
+)
+
+// Run is a live wandb run created by NewRun. OnStart fires when NewRun
+// creates the run. A background goroutine polls the run's health on a
+// fixed interval (see superviseHeartbeat); once consecutive heartbeat
+// failures exceed its tolerance it invokes OnHeartbeatFailure and, per
+// the returned action, either retries, recreates the run under
+// params.AutoRestart (firing OnStart again), or finishes it (firing
+// OnFinish). Finish also fires OnFinish.
+type Run struct {
+	client ServiceClient
+	params *runopts.RunParams
+
+	mu    sync.Mutex
+	id    string
+	state runopts.RunState
+
+	stopSupervisor chan struct{}
+	supervisorDone chan struct{}
+	stopOnce       sync.Once
+
+	finishOnce sync.Once
+	finishErr  error
+}
+
+var _ runopts.Run = (*Run)(nil)
+
+// RunID returns the server-assigned or user-supplied id of the run.
+func (r *Run) RunID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.id
+}
+
+// State returns the run's current lifecycle state.
+func (r *Run) State() runopts.RunState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+func (r *Run) setState(s runopts.RunState) {
+	r.mu.Lock()
+	r.state = s
+	r.mu.Unlock()
+}
+
+func (r *Run) setID(id string) {
+	r.mu.Lock()
+	r.id = id
+	r.mu.Unlock()
+}
+
+// NewRun resolves opts, resolves the run's resume mode against client,
+// creates the run on the internal service, and starts its supervisor
+// goroutine. OnStart fires here once the server has confirmed the run
+// started, and the supervisor goroutine's heartbeat polling drives
+// OnFinish and OnHeartbeatFailure for the rest of the run's life.
+func NewRun(client ServiceClient, opts ...runopts.RunOption) (*Run, error) {
+	params, err := runopts.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &Run{
+		client:         client,
+		params:         params,
+		state:          runopts.RunStatePending,
+		stopSupervisor: make(chan struct{}),
+		supervisorDone: make(chan struct{}),
+	}
+
+	if err := run.init(); err != nil {
+		return nil, err
+	}
+
+	go run.superviseHeartbeat()
+
+	return run, nil
+}
+
+// init creates (or resumes) the run on the internal service from
+// r.params, sets r's id and state, and invokes OnStart. It is used by
+// both NewRun and the supervisor's restart path, which calls it again
+// with Resume forced to "allow" to pick the recreated run back up.
+func (r *Run) init() error {
+	var runID string
+	if r.params.RunID != nil {
+		runID = *r.params.RunID
+	}
+
+	var resumed bool
+	if r.params.Resume != nil {
+		var err error
+		resumed, err = resolveResume(r.client, runID, *r.params.Resume)
+		if err != nil {
+			return err
+		}
+	}
+
+	record, err := r.client.InitRun(r.params.ToRunRecord(resumed))
+	if err != nil {
+		return fmt.Errorf("gowandb: initializing run: %w", err)
+	}
+
+	r.setID(record.RunId)
+	r.setState(runopts.RunStateRunning)
+
+	if r.params.OnStart != nil {
+		r.params.OnStart(r)
+	}
+
+	return nil
+}
+
+// Finish tells the internal service the run has finished, with the
+// error that caused it to finish, if any, stops the supervisor
+// goroutine, and invokes OnFinish. It is a no-op beyond the first call,
+// so it is safe to call even after the supervisor has already finished
+// the run on its own (e.g. by aborting after a heartbeat failure).
+func (r *Run) Finish(runErr error) error {
+	r.stopOnce.Do(func() { close(r.stopSupervisor) })
+	<-r.supervisorDone
+
+	return r.doFinish(runErr)
+}
+
+// doFinish sends FinishRun and invokes OnFinish exactly once, regardless
+// of whether it is reached via Finish or via the supervisor goroutine
+// finishing the run on its own.
+func (r *Run) doFinish(runErr error) error {
+	r.finishOnce.Do(func() {
+		state := runopts.RunStateFinished
+		if runErr != nil {
+			state = runopts.RunStateFailed
+		}
+		r.setState(state)
+
+		r.finishErr = r.client.FinishRun(r.RunID(), runErr)
+
+		if r.params.OnFinish != nil {
+			r.params.OnFinish(r, runErr)
+		}
+	})
+
+	return r.finishErr
+}
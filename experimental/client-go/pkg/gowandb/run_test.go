@@ -0,0 +1,218 @@
+package gowandb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	spb "github.com/wandb/wandb/core/pkg/service_go_proto"
+	"github.com/wandb/wandb/experimental/client-go/pkg/opts/runopts"
+)
+
+// fakeServiceClient is a fake ServiceClient that lets tests inject
+// existing-run state and failures, standing in for the wandb-core
+// internal service.
+type fakeServiceClient struct {
+	mu sync.Mutex
+
+	existingRunIDs map[string]bool
+	initErr        error
+	inits          []*spb.RunRecord
+	finishCalls    int
+
+	// heartbeatErrs is consumed in order, one per Heartbeat call; once
+	// exhausted, Heartbeat succeeds.
+	heartbeatErrs  []error
+	heartbeatCalls int
+}
+
+func (f *fakeServiceClient) RunExists(runID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.existingRunIDs[runID], nil
+}
+
+func (f *fakeServiceClient) InitRun(record *spb.RunRecord) (*spb.RunRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.initErr != nil {
+		return nil, f.initErr
+	}
+
+	out := *record
+	if out.RunId == "" {
+		out.RunId = fmt.Sprintf("run-%d", len(f.inits)+1)
+	}
+	f.inits = append(f.inits, &out)
+
+	return &out, nil
+}
+
+func (f *fakeServiceClient) FinishRun(runID string, runErr error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.finishCalls++
+	return nil
+}
+
+func (f *fakeServiceClient) Heartbeat(runID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	idx := f.heartbeatCalls
+	f.heartbeatCalls++
+	if idx < len(f.heartbeatErrs) {
+		return f.heartbeatErrs[idx]
+	}
+	return nil
+}
+
+func TestResolveResume(t *testing.T) {
+	cases := []struct {
+		name        string
+		mode        string
+		exists      bool
+		wantResumed bool
+		wantErr     bool
+	}{
+		{name: "allow-exists", mode: runopts.ResumeAllow, exists: true, wantResumed: true},
+		{name: "allow-missing", mode: runopts.ResumeAllow, exists: false, wantResumed: false},
+		{name: "must-exists", mode: runopts.ResumeMust, exists: true, wantResumed: true},
+		{name: "must-missing", mode: runopts.ResumeMust, exists: false, wantErr: true},
+		{name: "never-exists", mode: runopts.ResumeNever, exists: true, wantErr: true},
+		{name: "never-missing", mode: runopts.ResumeNever, exists: false, wantResumed: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeServiceClient{existingRunIDs: map[string]bool{"run1": tc.exists}}
+			resumed, err := resolveResume(client, "run1", tc.mode)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("resolveResume() = nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveResume() error = %v", err)
+			}
+			if resumed != tc.wantResumed {
+				t.Fatalf("resolveResume() = %v, want %v", resumed, tc.wantResumed)
+			}
+		})
+	}
+}
+
+func TestResolveResume_Auto(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir() error = %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	client := &fakeServiceClient{}
+
+	resumed, err := resolveResume(client, "run1", runopts.ResumeAuto)
+	if err != nil {
+		t.Fatalf("resolveResume() error = %v", err)
+	}
+	if resumed {
+		t.Fatalf("resolveResume() = true, want false before any local run file exists")
+	}
+
+	runDir := filepath.Join(localRunDir, "run-20260101_000000-run1")
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, "run-run1.wandb"), nil, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	resumed, err = resolveResume(client, "run1", runopts.ResumeAuto)
+	if err != nil {
+		t.Fatalf("resolveResume() error = %v", err)
+	}
+	if !resumed {
+		t.Fatalf("resolveResume() = false, want true once a local run file exists")
+	}
+}
+
+func TestNewRun_ResumeNeverFailsIfRunExists(t *testing.T) {
+	client := &fakeServiceClient{existingRunIDs: map[string]bool{"run1": true}}
+
+	_, err := NewRun(client, runopts.WithRunID("run1"), runopts.WithResume(runopts.ResumeNever))
+	if err == nil {
+		t.Fatalf("NewRun() = nil error, want error since run1 already exists")
+	}
+}
+
+func TestNewRun_MarshalsMetadataOntoRunRecord(t *testing.T) {
+	client := &fakeServiceClient{}
+
+	run, err := NewRun(client,
+		runopts.WithRunID("run1"),
+		runopts.WithProject("proj"),
+		runopts.WithEntity("ent"),
+		runopts.WithName("display-name"),
+		runopts.WithTags("a", "b"),
+		runopts.WithNotes("some notes"),
+		runopts.WithGroup("grp"),
+		runopts.WithJobType("train"),
+	)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+	t.Cleanup(func() { _ = run.Finish(nil) })
+
+	if len(client.inits) != 1 {
+		t.Fatalf("got %d InitRun calls, want 1", len(client.inits))
+	}
+	got := client.inits[0]
+
+	if got.Project != "proj" {
+		t.Errorf("Project = %q, want %q", got.Project, "proj")
+	}
+	if got.Entity != "ent" {
+		t.Errorf("Entity = %q, want %q", got.Entity, "ent")
+	}
+	if got.DisplayName != "display-name" {
+		t.Errorf("DisplayName = %q, want %q", got.DisplayName, "display-name")
+	}
+	if want := []string{"a", "b"}; len(got.Tags) != len(want) || got.Tags[0] != want[0] || got.Tags[1] != want[1] {
+		t.Errorf("Tags = %v, want %v", got.Tags, want)
+	}
+	if got.Notes != "some notes" {
+		t.Errorf("Notes = %q, want %q", got.Notes, "some notes")
+	}
+	if got.RunGroup != "grp" {
+		t.Errorf("RunGroup = %q, want %q", got.RunGroup, "grp")
+	}
+	if got.JobType != "train" {
+		t.Errorf("JobType = %q, want %q", got.JobType, "train")
+	}
+}
+
+func TestNewRun_ResumeMustSetsResumedOnRecord(t *testing.T) {
+	client := &fakeServiceClient{existingRunIDs: map[string]bool{"run1": true}}
+
+	run, err := NewRun(client, runopts.WithRunID("run1"), runopts.WithResume(runopts.ResumeMust))
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+	if !client.inits[0].Resumed {
+		t.Errorf("RunRecord.Resumed = false, want true for resume mode %q", runopts.ResumeMust)
+	}
+	if err := run.Finish(nil); err != nil {
+		t.Errorf("Finish() error = %v", err)
+	}
+	if client.finishCalls != 1 {
+		t.Errorf("finishCalls = %d, want 1", client.finishCalls)
+	}
+}
@@ -0,0 +1,29 @@
+// Package gowandb is the Go client for wandb runs: it creates runs and
+// drives them against the wandb-core internal service.
+package gowandb
+
+import (
+	spb "github.com/wandb/wandb/core/pkg/service_go_proto"
+)
+
+// ServiceClient is the seam gowandb uses to talk to the wandb-core
+// internal service. It is satisfied by the generated connection to the
+// core service in production and by a fake in tests.
+type ServiceClient interface {
+	// RunExists reports whether a run with the given id already exists
+	// on the server, for enforcing the "must"/"never"/"allow" resume
+	// modes.
+	RunExists(runID string) (bool, error)
+	// InitRun creates (or resumes) the run described by record and
+	// returns the server's view of it.
+	InitRun(record *spb.RunRecord) (*spb.RunRecord, error)
+	// FinishRun tells the server the run has finished, with the error
+	// that caused it to finish, if any.
+	FinishRun(runID string, runErr error) error
+	// Heartbeat checks that the run is still alive on the server. It is
+	// polled on a fixed interval by the run's supervisor goroutine (see
+	// superviseHeartbeat), not invoked per-record, and returns an error
+	// if the connection or the run itself has failed, which the
+	// supervisor reacts to.
+	Heartbeat(runID string) error
+}
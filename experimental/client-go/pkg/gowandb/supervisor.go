@@ -0,0 +1,115 @@
+package gowandb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wandb/wandb/experimental/client-go/pkg/opts/runopts"
+)
+
+// heartbeatInterval is how often the supervisor checks the run is still
+// alive on the server. It is a var, not a const, so tests can shrink it.
+var heartbeatInterval = 30 * time.Second
+
+// heartbeatFailureTolerance is how many consecutive heartbeat failures
+// the supervisor absorbs on its own, without consulting
+// OnHeartbeatFailure, before escalating. This keeps a single transient
+// network blip from aborting an otherwise-healthy run under the default
+// (no OnHeartbeatFailure) policy. It is a var, not a const, so tests can
+// shrink it.
+var heartbeatFailureTolerance = 3
+
+// superviseHeartbeat is the run's supervisor goroutine. It heartbeats
+// the run on a timer. The first heartbeatFailureTolerance consecutive
+// failures are silently retried; once that budget is exhausted, it asks
+// OnHeartbeatFailure (defaulting to HeartbeatAbort if unset) how to
+// react: retry, finish-and-recreate the run per params.AutoRestart, or
+// abort.
+func (r *Run) superviseHeartbeat() {
+	defer close(r.supervisorDone)
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	attempts := 0
+	consecutiveFailures := 0
+
+	for {
+		select {
+		case <-r.stopSupervisor:
+			return
+
+		case <-ticker.C:
+			err := r.client.Heartbeat(r.RunID())
+			if err == nil {
+				consecutiveFailures = 0
+				continue
+			}
+
+			consecutiveFailures++
+			if consecutiveFailures <= heartbeatFailureTolerance {
+				continue
+			}
+
+			action := runopts.HeartbeatAbort
+			if r.params.OnHeartbeatFailure != nil {
+				action = r.params.OnHeartbeatFailure(err)
+			}
+
+			switch action {
+			case runopts.HeartbeatRetry:
+				continue
+
+			case runopts.HeartbeatRestart:
+				if !r.allowRestart(&attempts) {
+					r.doFinish(err)
+					return
+				}
+				if restartErr := r.restart(err); restartErr != nil {
+					r.doFinish(restartErr)
+					return
+				}
+
+			case runopts.HeartbeatAbort:
+				r.doFinish(err)
+				return
+			}
+		}
+	}
+}
+
+// allowRestart reports whether params.AutoRestart permits another
+// restart attempt, applying its backoff and incrementing attempts if so.
+func (r *Run) allowRestart(attempts *int) bool {
+	policy := r.params.AutoRestart
+	if policy == nil || policy.Kind == runopts.RestartNever {
+		return false
+	}
+	if policy.MaxAttempts > 0 && *attempts >= policy.MaxAttempts {
+		return false
+	}
+
+	*attempts++
+	if policy.Backoff > 0 {
+		time.Sleep(policy.Backoff)
+	}
+
+	return true
+}
+
+// restart finishes the current run and recreates it with the same RunID
+// and Resume set to "allow", so it picks up where the failed run left
+// off.
+func (r *Run) restart(heartbeatErr error) error {
+	runID := r.RunID()
+
+	if err := r.client.FinishRun(runID, fmt.Errorf("gowandb: restarting run %q after heartbeat failure: %w", runID, heartbeatErr)); err != nil {
+		return fmt.Errorf("gowandb: finishing run %q before restart: %w", runID, err)
+	}
+
+	allow := runopts.ResumeAllow
+	r.params.RunID = &runID
+	r.params.Resume = &allow
+
+	return r.init()
+}
@@ -0,0 +1,217 @@
+package gowandb
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wandb/wandb/experimental/client-go/pkg/opts/runopts"
+)
+
+// setHeartbeatInterval shrinks the package-level heartbeat tick so tests
+// don't wait 30 real seconds for the supervisor to notice a failure. It
+// restores the original value on test cleanup.
+func setHeartbeatInterval(t *testing.T, d time.Duration) {
+	t.Helper()
+	orig := heartbeatInterval
+	heartbeatInterval = d
+	t.Cleanup(func() { heartbeatInterval = orig })
+}
+
+func TestSupervisor_OnStartFiresOnInit(t *testing.T) {
+	client := &fakeServiceClient{}
+
+	started := make(chan runopts.Run, 1)
+	run, err := NewRun(client,
+		runopts.WithRunID("run1"),
+		runopts.WithOnStart(func(r runopts.Run) { started <- r }),
+	)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+	t.Cleanup(func() { _ = run.Finish(nil) })
+
+	select {
+	case got := <-started:
+		if got.RunID() != "run1" {
+			t.Errorf("OnStart got run %q, want %q", got.RunID(), "run1")
+		}
+	default:
+		t.Fatalf("OnStart was not invoked")
+	}
+}
+
+func TestSupervisor_AbortsOnHeartbeatFailureByDefault(t *testing.T) {
+	setHeartbeatInterval(t, 5*time.Millisecond)
+
+	// One more than heartbeatFailureTolerance, so the supervisor exhausts
+	// its built-in retry budget and actually escalates to the default
+	// abort instead of silently absorbing a single transient failure.
+	errs := make([]error, heartbeatFailureTolerance+1)
+	for i := range errs {
+		errs[i] = errors.New("boom")
+	}
+	client := &fakeServiceClient{heartbeatErrs: errs}
+
+	finished := make(chan error, 1)
+	run, err := NewRun(client,
+		runopts.WithRunID("run1"),
+		runopts.WithOnFinish(func(_ runopts.Run, err error) { finished <- err }),
+	)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+
+	select {
+	case err := <-finished:
+		if err == nil {
+			t.Errorf("OnFinish error = nil, want the heartbeat failure")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for OnFinish after heartbeat failure")
+	}
+
+	if got := run.State(); got != runopts.RunStateFailed {
+		t.Errorf("State() = %v, want %v", got, runopts.RunStateFailed)
+	}
+
+	// Finish must be a no-op now: the supervisor already finished the run.
+	if err := run.Finish(nil); err != nil {
+		t.Errorf("Finish() error = %v", err)
+	}
+	if client.finishCalls != 1 {
+		t.Errorf("finishCalls = %d, want 1 (no double-finish)", client.finishCalls)
+	}
+}
+
+func TestSupervisor_RetryKeepsRunAlive(t *testing.T) {
+	setHeartbeatInterval(t, 5*time.Millisecond)
+
+	// Two more than heartbeatFailureTolerance, so the supervisor consults
+	// OnHeartbeatFailure (which answers HeartbeatRetry) twice in a row.
+	errs := make([]error, heartbeatFailureTolerance+2)
+	for i := range errs {
+		errs[i] = errors.New("transient")
+	}
+	client := &fakeServiceClient{heartbeatErrs: errs}
+
+	run, err := NewRun(client,
+		runopts.WithRunID("run1"),
+		runopts.WithOnHeartbeatFailure(func(error) runopts.HeartbeatAction { return runopts.HeartbeatRetry }),
+	)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		client.mu.Lock()
+		calls := client.heartbeatCalls
+		client.mu.Unlock()
+		if calls > len(client.heartbeatErrs) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for heartbeats past the injected failures (got %d)", calls)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := run.State(); got != runopts.RunStateRunning {
+		t.Errorf("State() = %v, want %v (retry must not finish the run)", got, runopts.RunStateRunning)
+	}
+	if err := run.Finish(nil); err != nil {
+		t.Errorf("Finish() error = %v", err)
+	}
+}
+
+func TestSupervisor_RestartsAndRespectsMaxAttempts(t *testing.T) {
+	setHeartbeatInterval(t, 5*time.Millisecond)
+
+	errs := make([]error, 10)
+	for i := range errs {
+		errs[i] = errors.New("boom")
+	}
+	client := &fakeServiceClient{heartbeatErrs: errs}
+
+	var starts int32
+	finished := make(chan struct{})
+
+	run, err := NewRun(client,
+		runopts.WithRunID("run1"),
+		runopts.WithOnStart(func(runopts.Run) { atomic.AddInt32(&starts, 1) }),
+		runopts.WithOnHeartbeatFailure(func(error) runopts.HeartbeatAction { return runopts.HeartbeatRestart }),
+		runopts.WithAutoRestart(runopts.RestartPolicy{Kind: runopts.RestartOnFailure, MaxAttempts: 2}),
+		runopts.WithOnFinish(func(runopts.Run, error) { close(finished) }),
+	)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the supervisor to give up after MaxAttempts")
+	}
+
+	if got := run.State(); got != runopts.RunStateFailed {
+		t.Errorf("State() = %v, want %v", got, runopts.RunStateFailed)
+	}
+	// One initial start plus one per restart (MaxAttempts=2).
+	if got := atomic.LoadInt32(&starts); got != 3 {
+		t.Errorf("OnStart called %d times, want 3", got)
+	}
+	if got := len(client.inits); got != 3 {
+		t.Errorf("InitRun called %d times, want 3", got)
+	}
+	// Each restart finishes the previous run, plus the final abort.
+	if client.finishCalls != 3 {
+		t.Errorf("finishCalls = %d, want 3", client.finishCalls)
+	}
+}
+
+func TestSupervisor_RestartPreservesRunID(t *testing.T) {
+	setHeartbeatInterval(t, 5*time.Millisecond)
+
+	// One more than heartbeatFailureTolerance, so the supervisor's retry
+	// budget is exhausted and it actually escalates to OnHeartbeatFailure.
+	errs := make([]error, heartbeatFailureTolerance+1)
+	for i := range errs {
+		errs[i] = errors.New("boom")
+	}
+	client := &fakeServiceClient{heartbeatErrs: errs}
+
+	run, err := NewRun(client,
+		runopts.WithRunID("run1"),
+		runopts.WithOnHeartbeatFailure(func(error) runopts.HeartbeatAction { return runopts.HeartbeatRestart }),
+		runopts.WithAutoRestart(runopts.RestartPolicy{Kind: runopts.RestartOnFailure, MaxAttempts: 1}),
+	)
+	if err != nil {
+		t.Fatalf("NewRun() error = %v", err)
+	}
+	t.Cleanup(func() { _ = run.Finish(nil) })
+
+	deadline := time.After(time.Second)
+	for {
+		client.mu.Lock()
+		n := len(client.inits)
+		client.mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for the restart's InitRun call")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	for i, record := range client.inits {
+		if record.RunId != "run1" {
+			t.Errorf("inits[%d].RunId = %q, want %q", i, record.RunId, "run1")
+		}
+	}
+}
@@ -2,16 +2,72 @@
 package runopts
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
 	spb "github.com/wandb/wandb/core/pkg/service_go_proto"
 	"github.com/wandb/wandb/experimental/client-go/pkg/runconfig"
 )
 
+// maxNotesLength matches the server-side limit on the length of a run's
+// notes field.
+const maxNotesLength = 65536
+
 type RunParams struct {
 	Config    *runconfig.Config
 	Name      *string
 	RunID     *string
 	Project   *string
 	Telemetry *spb.TelemetryRecord
+
+	// Resume controls how the run is picked up when RunID may already
+	// refer to a run on the server. See WithResume for the accepted
+	// values.
+	Resume *string
+
+	// Tags are short labels attached to the run for filtering in the UI.
+	Tags []string
+	// Notes is a longer free-form description of the run.
+	Notes *string
+	// Group ties related runs together, e.g. the runs of a sweep or the
+	// workers of a distributed experiment.
+	Group *string
+	// JobType describes the role this run plays within its Group, e.g.
+	// "train" or "eval".
+	JobType *string
+	// Entity is the user or team the run belongs to. Defaults to the
+	// caller's default entity if unset.
+	Entity *string
+
+	// OnStart, if set, is invoked once the server has confirmed the run
+	// started.
+	OnStart func(Run)
+	// OnFinish, if set, is invoked once the run has finished, with any
+	// error that caused it to finish (nil on a clean finish).
+	OnFinish func(Run, error)
+	// OnHeartbeatFailure, if set, is invoked when the supervisor's
+	// periodic heartbeat poll to the backend fails after exhausting its
+	// built-in retry tolerance, and its return value tells the
+	// supervisor how to react. If unset, such a failure aborts the run.
+	OnHeartbeatFailure func(error) HeartbeatAction
+	// AutoRestart governs whether the supervisor recreates the run after
+	// a heartbeat failure requests a restart. If nil, RestartNever
+	// applies.
+	AutoRestart *RestartPolicy
+
+	// settingsFiles and expandEnv drive deferred resolution in New: they
+	// are applied after all explicit options, so explicit options win
+	// over settings-file values, and env expansion then applies to the
+	// resulting fields, including those filled from a settings file. See
+	// WithSettingsFile and WithEnvExpansion.
+	settingsFiles []string
+	expandEnv     bool
 }
 
 type RunOption func(*RunParams)
@@ -39,3 +95,348 @@ func WithProject(project string) RunOption {
 		p.Project = &project
 	}
 }
+
+// Resume mode values accepted by WithResume, matching the `resume`
+// setting of the Python SDK.
+const (
+	ResumeAllow = "allow"
+	ResumeMust  = "must"
+	ResumeNever = "never"
+	ResumeAuto  = "auto"
+)
+
+// WithResume controls how an existing run identified by RunID should be
+// picked up, matching the Python SDK's `resume` setting:
+//
+//   - "allow": continue the run's history and step counters if RunID
+//     already exists on the server; otherwise start a new run.
+//   - "must": continue the run; fail if RunID does not already exist on
+//     the server.
+//   - "never": start a new run; fail if RunID already exists on the
+//     server.
+//   - "auto": resume only if a local .wandb file for RunID is present;
+//     otherwise start a new run.
+//
+// The mode is resolved by gowandb's run manager against the server (and,
+// for "auto", the local run directory) when the run is created, since it
+// requires a round trip. Validate only checks that mode is one of the
+// four accepted values.
+func WithResume(mode string) RunOption {
+	return func(p *RunParams) {
+		p.Resume = &mode
+	}
+}
+
+// WithTags attaches labels to the run for filtering in the UI. Tags must
+// be non-empty and unique; duplicates and blanks are rejected by
+// Validate.
+func WithTags(tags ...string) RunOption {
+	return func(p *RunParams) {
+		p.Tags = tags
+	}
+}
+
+// WithNotes sets a longer free-form description of the run. Notes longer
+// than the server's limit are rejected by Validate.
+func WithNotes(notes string) RunOption {
+	return func(p *RunParams) {
+		p.Notes = &notes
+	}
+}
+
+// WithGroup ties this run together with other related runs, e.g. the
+// runs of a sweep or the workers of a distributed experiment.
+func WithGroup(group string) RunOption {
+	return func(p *RunParams) {
+		p.Group = &group
+	}
+}
+
+// WithJobType describes the role this run plays within its Group, e.g.
+// "train" or "eval".
+func WithJobType(jobType string) RunOption {
+	return func(p *RunParams) {
+		p.JobType = &jobType
+	}
+}
+
+// WithEntity sets the user or team the run belongs to. If unset, the
+// caller's default entity is used.
+func WithEntity(entity string) RunOption {
+	return func(p *RunParams) {
+		p.Entity = &entity
+	}
+}
+
+// WithEnvExpansion applies os.ExpandEnv to Name, RunID, Project, Entity,
+// Group, and JobType, so callers can write e.g.
+// WithProject("$WANDB_PROJECT") and have it resolved by New. Expansion
+// runs after settings-file values have been filled in, so an env
+// reference left in a settings file is also expanded.
+func WithEnvExpansion() RunOption {
+	return func(p *RunParams) {
+		p.expandEnv = true
+	}
+}
+
+// WithSettingsFile loads a JSON or YAML settings file (matching the
+// layout of the Python SDK's settings.json, with keys like "project",
+// "entity", "run_name", "tags", "notes", "resume", "run_group", and
+// "job_type") and uses it to fill in any RunParams fields left unset by
+// other options. Explicit options always win over settings-file values.
+// The file is read by New, not when this option is constructed.
+func WithSettingsFile(path string) RunOption {
+	return func(p *RunParams) {
+		p.settingsFiles = append(p.settingsFiles, path)
+	}
+}
+
+// settingsFileData mirrors the subset of the Python SDK's settings.json
+// layout that RunParams understands.
+type settingsFileData struct {
+	Project  *string  `json:"project" yaml:"project"`
+	Entity   *string  `json:"entity" yaml:"entity"`
+	RunName  *string  `json:"run_name" yaml:"run_name"`
+	Tags     []string `json:"tags" yaml:"tags"`
+	Notes    *string  `json:"notes" yaml:"notes"`
+	Resume   *string  `json:"resume" yaml:"resume"`
+	RunGroup *string  `json:"run_group" yaml:"run_group"`
+	JobType  *string  `json:"job_type" yaml:"job_type"`
+}
+
+func loadSettingsFile(path string) (*settingsFileData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("runopts: reading settings file %q: %w", path, err)
+	}
+
+	var s settingsFileData
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &s)
+	default:
+		err = json.Unmarshal(data, &s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("runopts: parsing settings file %q: %w", path, err)
+	}
+
+	return &s, nil
+}
+
+// applySettingsFile fills in any field left unset by explicit options.
+func (p *RunParams) applySettingsFile(s *settingsFileData) {
+	if p.Project == nil {
+		p.Project = s.Project
+	}
+	if p.Entity == nil {
+		p.Entity = s.Entity
+	}
+	if p.Name == nil {
+		p.Name = s.RunName
+	}
+	if p.Tags == nil {
+		p.Tags = s.Tags
+	}
+	if p.Notes == nil {
+		p.Notes = s.Notes
+	}
+	if p.Resume == nil {
+		p.Resume = s.Resume
+	}
+	if p.Group == nil {
+		p.Group = s.RunGroup
+	}
+	if p.JobType == nil {
+		p.JobType = s.JobType
+	}
+}
+
+func expandStringField(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	expanded := os.ExpandEnv(*s)
+	return &expanded
+}
+
+// New resolves opts into a validated RunParams. Settings-file options and
+// env expansion are deferred until every explicit option has been
+// applied, so explicit options always win over settings-file values,
+// regardless of the order opts are passed in. Env expansion runs last and
+// rewrites $VAR references in whichever value each field ended up with;
+// environment variables are never used as field values on their own.
+func New(opts ...RunOption) (*RunParams, error) {
+	p := &RunParams{}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for _, path := range p.settingsFiles {
+		s, err := loadSettingsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p.applySettingsFile(s)
+	}
+
+	if p.expandEnv {
+		p.Name = expandStringField(p.Name)
+		p.RunID = expandStringField(p.RunID)
+		p.Project = expandStringField(p.Project)
+		p.Entity = expandStringField(p.Entity)
+		p.Group = expandStringField(p.Group)
+		p.JobType = expandStringField(p.JobType)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Validate checks that the accumulated options form a consistent set of
+// run parameters. gowandb calls this before marshaling the RunRecord
+// sent to the internal service.
+func (p *RunParams) Validate() error {
+	if p.Resume != nil {
+		switch *p.Resume {
+		case ResumeAllow, ResumeMust, ResumeNever, ResumeAuto:
+		default:
+			return fmt.Errorf("runopts: invalid resume mode %q, must be one of %q, %q, %q, %q",
+				*p.Resume, ResumeAllow, ResumeMust, ResumeNever, ResumeAuto)
+		}
+	}
+
+	if p.Notes != nil && len(*p.Notes) > maxNotesLength {
+		return fmt.Errorf("runopts: notes must be at most %d characters, got %d", maxNotesLength, len(*p.Notes))
+	}
+
+	seen := make(map[string]struct{}, len(p.Tags))
+	for _, tag := range p.Tags {
+		if tag == "" {
+			return fmt.Errorf("runopts: tags must not be empty")
+		}
+		if _, ok := seen[tag]; ok {
+			return fmt.Errorf("runopts: duplicate tag %q", tag)
+		}
+		seen[tag] = struct{}{}
+	}
+
+	return nil
+}
+
+// Run is the read-only handle lifecycle hooks receive. gowandb's run
+// manager implements it; the interface lives here, rather than a
+// concrete struct, so runopts does not import gowandb, which already
+// imports runopts for RunOption.
+type Run interface {
+	// RunID returns the server-assigned or user-supplied id of the run.
+	RunID() string
+	// State returns the run's current lifecycle state.
+	State() RunState
+}
+
+// RunState is the lifecycle stage of a Run as reported by the internal
+// handler.
+type RunState int
+
+const (
+	RunStatePending RunState = iota
+	RunStateRunning
+	RunStateFinished
+	RunStateFailed
+)
+
+func (s RunState) String() string {
+	switch s {
+	case RunStatePending:
+		return "pending"
+	case RunStateRunning:
+		return "running"
+	case RunStateFinished:
+		return "finished"
+	case RunStateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// HeartbeatAction tells the run supervisor how to react when a heartbeat
+// to the backend fails.
+type HeartbeatAction int
+
+const (
+	// HeartbeatRetry keeps the current run alive and retries.
+	HeartbeatRetry HeartbeatAction = iota
+	// HeartbeatRestart finishes the current run and recreates it with
+	// the same RunID and Resume set to "allow".
+	HeartbeatRestart
+	// HeartbeatAbort gives up and surfaces the failure to the caller.
+	HeartbeatAbort
+)
+
+// RestartKind selects the restart strategy used by WithAutoRestart.
+//
+// Restarts are only ever attempted from the supervisor's heartbeat-failure
+// path (see superviseHeartbeat in package gowandb); a clean Finish never
+// triggers one, so there is currently no kind for "restart unconditionally."
+type RestartKind int
+
+const (
+	// RestartNever never recreates the run.
+	RestartNever RestartKind = iota
+	// RestartOnFailure recreates the run when a heartbeat failure
+	// requested a restart, subject to MaxAttempts and Backoff.
+	RestartOnFailure
+)
+
+// RestartPolicy governs whether and how the supervisor recreates a run
+// after a heartbeat failure.
+type RestartPolicy struct {
+	Kind RestartKind
+	// MaxAttempts caps the number of times the run is recreated. Zero
+	// means unlimited.
+	MaxAttempts int
+	// Backoff is the delay before each restart attempt.
+	Backoff time.Duration
+}
+
+// WithOnStart registers a hook invoked once the server has confirmed the
+// run started.
+func WithOnStart(fn func(Run)) RunOption {
+	return func(p *RunParams) {
+		p.OnStart = fn
+	}
+}
+
+// WithOnFinish registers a hook invoked once the run has finished, with
+// any error that caused it to finish (nil on a clean finish).
+func WithOnFinish(fn func(Run, error)) RunOption {
+	return func(p *RunParams) {
+		p.OnFinish = fn
+	}
+}
+
+// WithOnHeartbeatFailure registers a hook invoked when the supervisor's
+// heartbeat poll has failed several times in a row, more than its
+// built-in retry tolerance for absorbing transient failures on its own
+// (see superviseHeartbeat in package gowandb). The hook's return value
+// tells the supervisor whether to retry, restart, or abort the run. If
+// unset, a heartbeat failure that exceeds the tolerance aborts the run.
+func WithOnHeartbeatFailure(fn func(error) HeartbeatAction) RunOption {
+	return func(p *RunParams) {
+		p.OnHeartbeatFailure = fn
+	}
+}
+
+// WithAutoRestart sets the policy the supervisor uses to recreate the
+// run after a heartbeat failure for which OnHeartbeatFailure returned
+// HeartbeatRestart. A run finished through Finish is never recreated.
+func WithAutoRestart(policy RestartPolicy) RunOption {
+	return func(p *RunParams) {
+		p.AutoRestart = &policy
+	}
+}
@@ -0,0 +1,197 @@
+package runopts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidate_ResumeMode(t *testing.T) {
+	cases := []struct {
+		name    string
+		mode    *string
+		wantErr bool
+	}{
+		{name: "unset", mode: nil},
+		{name: "allow", mode: ptr(ResumeAllow)},
+		{name: "must", mode: ptr(ResumeMust)},
+		{name: "never", mode: ptr(ResumeNever)},
+		{name: "auto", mode: ptr(ResumeAuto)},
+		{name: "garbage", mode: ptr("garbage"), wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &RunParams{Resume: tc.mode}
+			err := p.Validate()
+			if tc.wantErr && err == nil {
+				t.Fatalf("Validate() = nil, want error for resume mode %v", tc.mode)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidate_Tags(t *testing.T) {
+	cases := []struct {
+		name    string
+		tags    []string
+		wantErr bool
+	}{
+		{name: "unique", tags: []string{"a", "b"}},
+		{name: "empty-tag", tags: []string{"a", ""}, wantErr: true},
+		{name: "duplicate", tags: []string{"a", "a"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &RunParams{Tags: tc.tags}
+			err := p.Validate()
+			if tc.wantErr && err == nil {
+				t.Fatalf("Validate() = nil, want error for tags %v", tc.tags)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidate_NotesLength(t *testing.T) {
+	notes := strRepeat("x", maxNotesLength+1)
+	p := &RunParams{Notes: &notes}
+	if err := p.Validate(); err == nil {
+		t.Fatalf("Validate() = nil, want error for over-long notes")
+	}
+}
+
+func strRepeat(s string, n int) string {
+	b := make([]byte, 0, n)
+	for len(b) < n {
+		b = append(b, s...)
+	}
+	return string(b[:n])
+}
+
+func TestNew_ExplicitOptionWinsOverSettingsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	writeFile(t, path, `{"project": "file-project", "run_group": "file-group"}`)
+
+	p, err := New(
+		WithProject("explicit-project"),
+		WithSettingsFile(path),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := *p.Project; got != "explicit-project" {
+		t.Errorf("Project = %q, want %q", got, "explicit-project")
+	}
+	if got := *p.Group; got != "file-group" {
+		t.Errorf("Group = %q, want %q (filled from settings file)", got, "file-group")
+	}
+}
+
+func TestNew_SettingsFileOrderIndependent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	writeFile(t, path, `{"project": "file-project"}`)
+
+	// WithSettingsFile is passed before WithProject; explicit options
+	// still win regardless of call order.
+	p, err := New(
+		WithSettingsFile(path),
+		WithProject("explicit-project"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := *p.Project; got != "explicit-project" {
+		t.Errorf("Project = %q, want %q", got, "explicit-project")
+	}
+}
+
+func TestNew_YAMLSettingsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.yaml")
+	writeFile(t, path, "entity: file-entity\njob_type: train\n")
+
+	p, err := New(WithSettingsFile(path))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := *p.Entity; got != "file-entity" {
+		t.Errorf("Entity = %q, want %q", got, "file-entity")
+	}
+	if got := *p.JobType; got != "train" {
+		t.Errorf("JobType = %q, want %q", got, "train")
+	}
+}
+
+func TestNew_EnvExpansion(t *testing.T) {
+	t.Setenv("RUNOPTS_TEST_PROJECT", "env-project")
+	t.Setenv("RUNOPTS_TEST_GROUP", "env-group")
+	t.Setenv("RUNOPTS_TEST_JOBTYPE", "env-jobtype")
+
+	p, err := New(
+		WithProject("$RUNOPTS_TEST_PROJECT"),
+		WithGroup("$RUNOPTS_TEST_GROUP"),
+		WithJobType("$RUNOPTS_TEST_JOBTYPE"),
+		WithEnvExpansion(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := *p.Project; got != "env-project" {
+		t.Errorf("Project = %q, want %q", got, "env-project")
+	}
+	if got := *p.Group; got != "env-group" {
+		t.Errorf("Group = %q, want %q", got, "env-group")
+	}
+	if got := *p.JobType; got != "env-jobtype" {
+		t.Errorf("JobType = %q, want %q", got, "env-jobtype")
+	}
+}
+
+func TestNew_EnvExpansionAppliesAfterSettingsFile(t *testing.T) {
+	t.Setenv("RUNOPTS_TEST_ENTITY", "env-entity")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	writeFile(t, path, `{"entity": "$RUNOPTS_TEST_ENTITY"}`)
+
+	p, err := New(
+		WithSettingsFile(path),
+		WithEnvExpansion(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := *p.Entity; got != "env-entity" {
+		t.Errorf("Entity = %q, want %q (expanded after being filled from settings file)", got, "env-entity")
+	}
+}
+
+func TestNew_WithoutEnvExpansionLeavesReferenceLiteral(t *testing.T) {
+	t.Setenv("RUNOPTS_TEST_PROJECT", "env-project")
+
+	p, err := New(WithProject("$RUNOPTS_TEST_PROJECT"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := *p.Project; got != "$RUNOPTS_TEST_PROJECT" {
+		t.Errorf("Project = %q, want literal %q (no WithEnvExpansion)", got, "$RUNOPTS_TEST_PROJECT")
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("writing %q: %v", path, err)
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,43 @@
+package runopts
+
+import (
+	spb "github.com/wandb/wandb/core/pkg/service_go_proto"
+)
+
+// ToRunRecord marshals the accumulated options into the RunRecord sent to
+// the internal service when the run is created. resumed is the outcome
+// of resolving Resume against the server (and, for "auto", the local run
+// directory); that resolution requires a round trip gowandb performs, so
+// it is passed in rather than computed here. Callers should call
+// Validate before ToRunRecord.
+func (p *RunParams) ToRunRecord(resumed bool) *spb.RunRecord {
+	record := &spb.RunRecord{
+		Telemetry: p.Telemetry,
+		Tags:      p.Tags,
+		Resumed:   resumed,
+	}
+
+	if p.RunID != nil {
+		record.RunId = *p.RunID
+	}
+	if p.Project != nil {
+		record.Project = *p.Project
+	}
+	if p.Entity != nil {
+		record.Entity = *p.Entity
+	}
+	if p.Name != nil {
+		record.DisplayName = *p.Name
+	}
+	if p.Notes != nil {
+		record.Notes = *p.Notes
+	}
+	if p.Group != nil {
+		record.RunGroup = *p.Group
+	}
+	if p.JobType != nil {
+		record.JobType = *p.JobType
+	}
+
+	return record
+}